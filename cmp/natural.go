@@ -0,0 +1,101 @@
+package cmp
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Natural compares two strings the way humans usually expect filenames and
+// other numbered identifiers to sort: runs of ASCII digits are compared by
+// their numeric value rather than byte-by-byte, so "file2" sorts before
+// "file10", which in turn sorts before "file10a".
+//
+// Outside of digit runs, strings are compared rune by rune. When one string
+// is a prefix of the other, the shorter one sorts first.
+func Natural(l, r string) int {
+	return natural(l, r, false)
+}
+
+// NaturalFold is like [Natural], but additionally folds case, so that
+// "File2" and "file2" compare equal.
+func NaturalFold(l, r string) int {
+	return natural(l, r, true)
+}
+
+// NaturalFunc is like [Natural], but for any string type T.
+func NaturalFunc[T ~string](l, r T) int {
+	return natural(string(l), string(r), false)
+}
+
+func natural(l, r string, fold bool) int {
+	for {
+		if l == "" || r == "" {
+			return Compare(len(l), len(r))
+		}
+		lDigit, rDigit := isDigit(l[0]), isDigit(r[0])
+		switch {
+		case lDigit && rDigit:
+			var lRun, rRun string
+			lRun, l = digitRun(l)
+			rRun, r = digitRun(r)
+			if c := compareDigitRuns(lRun, rRun); c != 0 {
+				return c
+			}
+		case lDigit != rDigit:
+			// Fall through to a rune comparison: a digit is just another
+			// rune here, so "a" vs "1" is ordered by rune value like
+			// anything else.
+			fallthrough
+		default:
+			lr, lSize := utf8.DecodeRuneInString(l)
+			rr, rSize := utf8.DecodeRuneInString(r)
+			if fold {
+				lr, rr = unicode.ToLower(lr), unicode.ToLower(rr)
+			}
+			if c := Compare(lr, rr); c != 0 {
+				return c
+			}
+			l, r = l[lSize:], r[rSize:]
+		}
+	}
+}
+
+func isDigit(b byte) bool {
+	return '0' <= b && b <= '9'
+}
+
+// digitRun splits off the leading run of ASCII digits from s, returning the
+// run and the remainder.
+func digitRun(s string) (run, rest string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// compareDigitRuns compares two runs of ASCII digits by numeric value:
+// leading zeros are skipped, then the runs are compared first by length
+// (longer is larger, since both have no further leading zeros) and finally
+// lexically.
+func compareDigitRuns(l, r string) int {
+	l = trimLeadingZeros(l)
+	r = trimLeadingZeros(r)
+	if c := Compare(len(l), len(r)); c != 0 {
+		return c
+	}
+	if l < r {
+		return -1
+	} else if l > r {
+		return 1
+	}
+	return 0
+}
+
+func trimLeadingZeros(s string) string {
+	i := 0
+	for i < len(s)-1 && s[i] == '0' {
+		i++
+	}
+	return s[i:]
+}
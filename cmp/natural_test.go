@@ -0,0 +1,75 @@
+package cmp_test
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/slices"
+	"gonih.org/cmp"
+)
+
+func TestNatural(t *testing.T) {
+	tcs := []struct {
+		l, r string
+		want int
+	}{
+		{"file2", "file10", -1},
+		{"file10", "file10a", -1},
+		{"file10a", "file2", 1},
+		{"file2", "file2", 0},
+		{"file02", "file2", 0},
+		{"file002", "file10", -1},
+		{"a", "ab", -1},
+		{"", "", 0},
+		{"", "a", -1},
+		{"img1.png", "img1.png", 0},
+	}
+	for _, tc := range tcs {
+		if got := cmp.Natural(tc.l, tc.r); got != tc.want {
+			t.Errorf("Natural(%q, %q) = %d, want %d", tc.l, tc.r, got, tc.want)
+		}
+	}
+}
+
+func TestNaturalFold(t *testing.T) {
+	tcs := []struct {
+		l, r string
+		want int
+	}{
+		{"File2", "file2", 0},
+		{"FILE2", "file10", -1},
+		{"File10a", "file2", 1},
+	}
+	for _, tc := range tcs {
+		if got := cmp.NaturalFold(tc.l, tc.r); got != tc.want {
+			t.Errorf("NaturalFold(%q, %q) = %d, want %d", tc.l, tc.r, got, tc.want)
+		}
+	}
+}
+
+func ExampleNatural() {
+	names := []string{"img10a.png", "img2.png", "img1.png", "img10.png"}
+	slices.SortFunc(names, cmp.Cmp[string](cmp.Natural).Less)
+	for _, n := range names {
+		fmt.Println(n)
+	}
+	// Output:
+	// img1.png
+	// img2.png
+	// img10.png
+	// img10a.png
+}
+
+var naturalBenchStrings = [2]string{"file1000a", "file1000b"}
+
+func BenchmarkNatural(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		cmp.Natural(naturalBenchStrings[0], naturalBenchStrings[1])
+	}
+}
+
+func BenchmarkCompareString(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		cmp.Compare(naturalBenchStrings[0], naturalBenchStrings[1])
+	}
+}
@@ -0,0 +1,186 @@
+// Package heap implements a generic binary heap on top of the comparators
+// from [gonih.org/cmp].
+//
+// Unlike the standard library's container/heap, this package does not
+// require implementing an interface; any cmp.Cmp[T] (as produced by
+// cmp.Chain, cmp.By, cmp.Reverse, ...) is enough to get a working min-heap.
+package heap
+
+import (
+	"iter"
+
+	"gonih.org/cmp"
+)
+
+// A Heap is a binary heap of elements of type T, ordered by a cmp.Cmp[T].
+// The zero value is not usable; use New or Heapify to construct one.
+type Heap[T any] struct {
+	cmp    cmp.Cmp[T]
+	v      []T
+	onMove func(to, from int)
+}
+
+// New returns an empty Heap using cmp to order elements. The element that
+// compares smallest under cmp is always at the top of the heap.
+func New[T any](cmp cmp.Cmp[T]) *Heap[T] {
+	return &Heap[T]{cmp: cmp}
+}
+
+// Heapify builds a Heap from v, ordered by cmp, in O(len(v)) time. It takes
+// ownership of v; the caller should not use v after calling Heapify.
+func Heapify[T any](cmp cmp.Cmp[T], v []T) *Heap[T] {
+	h := &Heap[T]{cmp: cmp, v: v}
+	for i := len(v)/2 - 1; i >= 0; i-- {
+		h.siftDown(i)
+	}
+	return h
+}
+
+// Len returns the number of elements in the heap.
+func (h *Heap[T]) Len() int {
+	return len(h.v)
+}
+
+// Push adds v to the heap.
+func (h *Heap[T]) Push(v T) {
+	i := len(h.v)
+	h.v = append(h.v, v)
+	if h.onMove != nil {
+		h.onMove(i, i)
+	}
+	h.siftUp(i)
+}
+
+// Pop removes and returns the smallest element in the heap. It panics if the
+// heap is empty.
+func (h *Heap[T]) Pop() T {
+	top := h.v[0]
+	n := len(h.v) - 1
+	h.v[0] = h.v[n]
+	h.v = h.v[:n]
+	if h.onMove != nil && n > 0 {
+		h.onMove(0, n)
+	}
+	if n > 0 {
+		h.siftDown(0)
+	}
+	return top
+}
+
+// Peek returns the smallest element in the heap, without removing it. It
+// panics if the heap is empty.
+func (h *Heap[T]) Peek() T {
+	return h.v[0]
+}
+
+// At returns the element currently stored at heap-internal index i. It is
+// meant to be called from an OnMove callback, to look up the element that
+// just moved.
+func (h *Heap[T]) At(i int) T {
+	return h.v[i]
+}
+
+// OnMove registers f to be called whenever the heap moves an element to a
+// new index, as to, from. f is called with the same index twice (to == from)
+// for an element that Push appends without moving further.
+//
+// Fix and Remove take a heap-internal index, but Push does not return one,
+// and indices silently change as the heap reorders on every Push, Pop, Fix
+// and Remove. A caller that needs to call Fix or Remove on an element other
+// than the current top should store an index alongside that element (e.g. in
+// a field reachable via At(to)) and keep it up to date from an OnMove
+// callback.
+func (h *Heap[T]) OnMove(f func(to, from int)) {
+	h.onMove = f
+}
+
+// Fix re-establishes the heap ordering after the element at index i has
+// changed. i must be in [0, h.Len()).
+func (h *Heap[T]) Fix(i int) {
+	if !h.siftDown(i) {
+		h.siftUp(i)
+	}
+}
+
+// Remove removes and returns the element at index i. i must be in
+// [0, h.Len()).
+func (h *Heap[T]) Remove(i int) T {
+	n := len(h.v) - 1
+	v := h.v[i]
+	h.v[i] = h.v[n]
+	h.v = h.v[:n]
+	if h.onMove != nil && i < n {
+		h.onMove(i, n)
+	}
+	if i < n {
+		h.Fix(i)
+	}
+	return v
+}
+
+func (h *Heap[T]) swap(i, j int) {
+	h.v[i], h.v[j] = h.v[j], h.v[i]
+	if h.onMove != nil {
+		h.onMove(i, j)
+		h.onMove(j, i)
+	}
+}
+
+func (h *Heap[T]) siftUp(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if h.cmp(h.v[i], h.v[parent]) >= 0 {
+			break
+		}
+		h.swap(i, parent)
+		i = parent
+	}
+}
+
+// siftDown moves the element at i down until the heap property is restored.
+// It reports whether any swap was made.
+func (h *Heap[T]) siftDown(i int) bool {
+	n := len(h.v)
+	moved := false
+	for {
+		child := 2*i + 1
+		if child >= n {
+			break
+		}
+		if right := child + 1; right < n && h.cmp(h.v[right], h.v[child]) < 0 {
+			child = right
+		}
+		if h.cmp(h.v[child], h.v[i]) >= 0 {
+			break
+		}
+		h.swap(i, child)
+		i = child
+		moved = true
+	}
+	return moved
+}
+
+// TopK returns the k smallest elements of seq under less, in ascending order.
+// If seq yields fewer than k elements, TopK returns all of them. It runs in
+// O(n log k) time using a bounded max-heap.
+func TopK[T any](less cmp.Cmp[T], k int, seq iter.Seq[T]) []T {
+	if k <= 0 {
+		return nil
+	}
+	h := New(cmp.Reverse(less))
+	for v := range seq {
+		if h.Len() < k {
+			h.Push(v)
+			continue
+		}
+		if less(v, h.Peek()) < 0 {
+			h.Pop()
+			h.Push(v)
+		}
+	}
+	out := make([]T, h.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = h.Pop()
+	}
+	return out
+}
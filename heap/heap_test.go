@@ -0,0 +1,128 @@
+package heap_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"gonih.org/cmp"
+	"gonih.org/heap"
+)
+
+func TestHeap(t *testing.T) {
+	h := heap.New(cmp.Compare[int])
+	for _, v := range []int{5, 3, 8, 1, 9, 2} {
+		h.Push(v)
+	}
+	var got []int
+	for h.Len() > 0 {
+		got = append(got, h.Pop())
+	}
+	want := []int{1, 2, 3, 5, 8, 9}
+	if !slices.Equal(got, want) {
+		t.Errorf("Pop sequence = %v, want %v", got, want)
+	}
+}
+
+func TestHeapify(t *testing.T) {
+	in := []int{5, 3, 8, 1, 9, 2}
+	h := heap.Heapify(cmp.Compare[int], in)
+	var got []int
+	for h.Len() > 0 {
+		got = append(got, h.Pop())
+	}
+	want := []int{1, 2, 3, 5, 8, 9}
+	if !slices.Equal(got, want) {
+		t.Errorf("Pop sequence = %v, want %v", got, want)
+	}
+}
+
+func TestFixRemove(t *testing.T) {
+	h := heap.Heapify(cmp.Compare[int], []int{5, 3, 8, 1, 9, 2})
+
+	h.Remove(0) // removes the smallest element, 1
+	var got []int
+	for h.Len() > 0 {
+		got = append(got, h.Pop())
+	}
+	want := []int{2, 3, 5, 8, 9}
+	if !slices.Equal(got, want) {
+		t.Errorf("Pop sequence after Remove = %v, want %v", got, want)
+	}
+}
+
+// item tracks its own heap index via OnMove, so a change to its value can be
+// followed by a direct Fix(item.idx) instead of only ever fixing the top.
+type item struct {
+	v   int
+	idx int
+}
+
+func TestFix(t *testing.T) {
+	items := []*item{{v: 5}, {v: 3}, {v: 8}, {v: 1}, {v: 9}, {v: 2}}
+	h := heap.New(cmp.ByFunc(func(it *item) int { return it.v }, cmp.Compare[int]))
+	h.OnMove(func(to, from int) { h.At(to).idx = to })
+	for _, it := range items {
+		h.Push(it)
+	}
+
+	// Lower the 8 to a new minimum and re-fix it from its tracked index.
+	for _, it := range items {
+		if it.v == 8 {
+			it.v = 0
+			h.Fix(it.idx)
+			break
+		}
+	}
+
+	var got []int
+	for h.Len() > 0 {
+		got = append(got, h.Pop().v)
+	}
+	want := []int{0, 1, 2, 3, 5, 9}
+	if !slices.Equal(got, want) {
+		t.Errorf("Pop sequence after Fix = %v, want %v", got, want)
+	}
+}
+
+func TestTopK(t *testing.T) {
+	got := heap.TopK(cmp.Compare[int], 3, slices.Values([]int{5, 3, 8, 1, 9, 2}))
+	want := []int{1, 2, 3}
+	if !slices.Equal(got, want) {
+		t.Errorf("TopK = %v, want %v", got, want)
+	}
+}
+
+func ExampleHeap_chain() {
+	// Keep the oldest person per name at the top, sorted by name.
+	type Person struct {
+		Name string
+		Age  int
+	}
+	people := []Person{
+		{"Paula", 42},
+		{"Joziah", 23},
+		{"Austin", 32},
+		{"Caleb", 23},
+		{"Paula", 37},
+		{"Austin", 45},
+	}
+
+	h := heap.New(cmp.Chain(
+		cmp.By(func(p Person) string { return p.Name }),
+		cmp.Reverse(cmp.By(func(p Person) int { return p.Age })),
+	))
+	for _, p := range people {
+		h.Push(p)
+	}
+	for h.Len() > 0 {
+		fmt.Println(h.Pop())
+	}
+	// Output:
+	// {Austin 45}
+	// {Austin 32}
+	// {Caleb 23}
+	// {Joziah 23}
+	// {Paula 42}
+	// {Paula 37}
+}
@@ -1,101 +1,345 @@
-// Package lines wraps a Reader to map file offsets to line information.
-//
-// The canonical use case are parsers like encoding/xml and encoding/json. They
-// report errors and token positions as byte-offsets in the input. When
-// reporting errors to the user, these offsets are mostly useless. This package
-// allows translating them into line/colum numbers. See the examples for how to
-// do that.
-package lines
-
-import (
-	"bytes"
-	"io"
-	"sort"
-	"sync"
-)
-
-// A Reader wraps an io.Reader and keeps track of line information read through
-// it. It is safe for concurrent use.
-type Reader struct {
-	mu    sync.RWMutex
-	r     io.Reader
-	offs  int64
-	lines []int64
-}
-
-// NewReader wrap r to keep track of line information.
-func NewReader(r io.Reader) *Reader {
-	return &Reader{r: r}
-}
-
-// Read passes all calls through to the underlying io.Reader, recording line
-// endings encountered in the streamed data.
-func (r *Reader) Read(p []byte) (n int, err error) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	n, err = r.r.Read(p)
-	for i := 0; i < n; {
-		j := bytes.IndexByte(p[i:n], '\n')
-		if j < 0 {
-			break
-		}
-		i += j + 1
-		r.lines = append(r.lines, r.offs+int64(i))
-	}
-	r.offs += int64(n)
-	return n, err
-}
-
-// Size is the number of bytes read so far. Position information is only
-// accurate for offsets less than Size.
-func (r *Reader) Size() int64 {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	return r.offs
-}
-
-// Position returns the line and column of given offset (in bytes). Lines and
-// columns are numbered starting with 1. End-of-line markers are counted as
-// part of the line preceding them. "\n" is used as an end-of-line marker,
-// which also covers systems where "\r\n" is canonically used.
-//
-// The returned information is only accurate if offset is less than Size.
-func (r *Reader) Position(offs int64) (line, column int) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	if offs < 0 {
-		panic("Position called with negative offset")
-	}
-	i := sort.Search(len(r.lines), func(i int) bool {
-		return offs < r.lines[i]
-	})
-	if i == 0 {
-		return 1, int(offs + 1)
-	}
-	return i + 1, int(offs - r.lines[i-1] + 1)
-}
-
-// Line is like Position, but only returns the line.
-func (r *Reader) Line(offs int64) int {
-	l, _ := r.Position(offs)
-	return l
-}
-
-// Column is like Position, but only returns the column.
-func (r *Reader) Column(offs int64) int {
-	_, c := r.Position(offs)
-	return c
-}
-
-// Reset the recorded position information and continue reading from nr.
-func (r *Reader) Reset(nr io.Reader) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	r.r = nr
-	r.offs = 0
-	r.lines = r.lines[:0]
-}
+// Package lines wraps a Reader to map file offsets to line information.
+//
+// The canonical use case are parsers like encoding/xml and encoding/json. They
+// report errors and token positions as byte-offsets in the input. When
+// reporting errors to the user, these offsets are mostly useless. This package
+// allows translating them into line/colum numbers, and back, via Position and
+// Offset. See the examples for how to do that.
+package lines
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Mode selects how Reader counts columns within a line.
+type Mode int
+
+const (
+	// ColumnsBytes counts columns as raw bytes, the same as counting
+	// offsets within the line. This is the default, and matches the
+	// package's original behavior.
+	ColumnsBytes Mode = iota
+	// ColumnsRunes decodes the line as UTF-8 and counts columns as
+	// decoded code points.
+	ColumnsRunes
+	// ColumnsDisplay is like ColumnsRunes, but additionally expands tabs
+	// to the next multiple of TabWidth and treats non-spacing combining
+	// marks (unicode.Mn) as zero-width, approximating the column a
+	// terminal or editor would display the rune at.
+	ColumnsDisplay
+)
+
+// LineEndings selects which byte sequences Reader recognizes as line
+// terminators.
+type LineEndings int
+
+const (
+	// LF recognizes only "\n" as a line terminator. This is the default,
+	// and matches the package's original behavior.
+	LF LineEndings = iota
+	// CRLF additionally recognizes "\r\n" and a bare "\r" as line
+	// terminators. As with "\n", the terminator is counted as part of the
+	// line it ends.
+	CRLF
+)
+
+// ringSize bounds the number of recently-completed lines Reader buffers to
+// answer Position and Offset for readers that are not an io.ReaderAt. Lines
+// older than this are still counted correctly, but ColumnsRunes and
+// ColumnsDisplay fall back to byte columns for them.
+const ringSize = 32
+
+// Options configures a Reader. The zero value selects ColumnsBytes columns
+// and LF line endings, matching the package's original behavior.
+type Options struct {
+	Mode        Mode
+	LineEndings LineEndings
+	// TabWidth is the tab stop width used by ColumnsDisplay. Zero selects
+	// the default of 8.
+	TabWidth int
+}
+
+// A Reader wraps an io.Reader and keeps track of line information read through
+// it. It is safe for concurrent use.
+type Reader struct {
+	mu   sync.RWMutex
+	r    io.Reader
+	ra   io.ReaderAt // non-nil if r implements io.ReaderAt
+	opts Options
+
+	offs  int64
+	lines []int64 // offset of the first byte of line i+1, for completed lines 0..len(lines)-1
+
+	pendingCR bool // saw a '\r' that may still turn into "\r\n"
+
+	cur  []byte              // content of the current, not yet terminated, line
+	ring [ringSize]ringEntry // content of recently completed lines, keyed by line index
+}
+
+type ringEntry struct {
+	idx int // 0-based line index; -1 if unset
+	b   []byte
+}
+
+// NewReader wrap r to keep track of line information, using ColumnsBytes
+// columns and LF line endings.
+func NewReader(r io.Reader) *Reader {
+	return NewReaderOptions(r, Options{})
+}
+
+// NewReaderOptions is like NewReader, but allows configuring the column mode
+// and recognized line endings.
+//
+// If r also implements io.ReaderAt, it is used to re-read line content for
+// Position and Offset on demand, instead of buffering it. Otherwise, Reader
+// keeps a bounded buffer of recently read lines.
+func NewReaderOptions(r io.Reader, opts Options) *Reader {
+	if opts.TabWidth <= 0 {
+		opts.TabWidth = 8
+	}
+	ra, _ := r.(io.ReaderAt)
+	rd := &Reader{r: r, ra: ra, opts: opts}
+	for i := range rd.ring {
+		rd.ring[i].idx = -1
+	}
+	return rd
+}
+
+// Read passes all calls through to the underlying io.Reader, recording line
+// endings encountered in the streamed data.
+func (r *Reader) Read(p []byte) (n int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, err = r.r.Read(p)
+	track := r.opts.Mode != ColumnsBytes && r.ra == nil
+	for i := 0; i < n; i++ {
+		b := p[i]
+		pos := r.offs + int64(i) + 1
+		if r.pendingCR {
+			r.pendingCR = false
+			if b == '\n' {
+				// "\r\n" is a single terminator for the line it ends.
+				r.lines[len(r.lines)-1] = pos
+				continue
+			}
+		}
+		if track {
+			r.cur = append(r.cur, b)
+		}
+		switch {
+		case b == '\n':
+			r.endLine(pos, track)
+		case b == '\r' && r.opts.LineEndings == CRLF:
+			r.endLine(pos, track)
+			r.pendingCR = true
+		}
+	}
+	r.offs += int64(n)
+	return n, err
+}
+
+// endLine records a line terminator ending at pos and, if track is set,
+// archives the current line's content for later lookups.
+func (r *Reader) endLine(pos int64, track bool) {
+	r.lines = append(r.lines, pos)
+	if track {
+		idx := len(r.lines) - 1
+		r.ring[idx%ringSize] = ringEntry{idx: idx, b: append([]byte(nil), r.cur...)}
+		r.cur = r.cur[:0]
+	}
+}
+
+// Size is the number of bytes read so far. Position information is only
+// accurate for offsets less than Size.
+func (r *Reader) Size() int64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.offs
+}
+
+// Position returns the line and column of given offset (in bytes). Lines and
+// columns are numbered starting with 1. End-of-line markers are counted as
+// part of the line preceding them.
+//
+// By default (see Options), "\n" is used as an end-of-line marker, which also
+// covers systems where "\r\n" is canonically used, and columns count bytes.
+// NewReaderOptions can select other line endings and, via Mode, count columns
+// as runes or as they would be displayed.
+//
+// The returned information is only accurate if offset is less than Size.
+func (r *Reader) Position(offs int64) (line, column int) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if offs < 0 {
+		panic("Position called with negative offset")
+	}
+	idx, start := r.findLine(offs)
+	if r.opts.Mode == ColumnsBytes {
+		return idx + 1, int(offs-start) + 1
+	}
+	b, ok := r.content(idx, start, offs)
+	if !ok {
+		return idx + 1, int(offs-start) + 1
+	}
+	return idx + 1, r.column(b)
+}
+
+// Offset returns the byte offset of the given line and column, the inverse of
+// Position. Lines and columns are numbered starting with 1.
+//
+// The returned information is only accurate for lines fully contained in the
+// bytes read so far, i.e. all but possibly the last one.
+func (r *Reader) Offset(line, column int) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if line < 1 || column < 1 {
+		return 0, fmt.Errorf("lines: invalid position %d:%d", line, column)
+	}
+	idx := line - 1
+	if idx > len(r.lines) {
+		return 0, fmt.Errorf("lines: line %d not read yet", line)
+	}
+	var start int64
+	if idx > 0 {
+		start = r.lines[idx-1]
+	}
+	if r.opts.Mode == ColumnsBytes {
+		return start + int64(column-1), nil
+	}
+	b, ok := r.fullLine(idx, start)
+	if !ok {
+		return start + int64(column-1), nil
+	}
+	n, err := r.columnOffset(b, column)
+	if err != nil {
+		return 0, err
+	}
+	return start + n, nil
+}
+
+// Line is like Position, but only returns the line.
+func (r *Reader) Line(offs int64) int {
+	l, _ := r.Position(offs)
+	return l
+}
+
+// Column is like Position, but only returns the column.
+func (r *Reader) Column(offs int64) int {
+	_, c := r.Position(offs)
+	return c
+}
+
+// Reset the recorded position information and continue reading from nr.
+func (r *Reader) Reset(nr io.Reader) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.r = nr
+	r.ra, _ = nr.(io.ReaderAt)
+	r.offs = 0
+	r.lines = r.lines[:0]
+	r.cur = r.cur[:0]
+	r.pendingCR = false
+	for i := range r.ring {
+		r.ring[i].idx = -1
+	}
+}
+
+// findLine returns the 0-based index of the line containing offs, and the
+// offset of that line's first byte.
+func (r *Reader) findLine(offs int64) (idx int, start int64) {
+	i := sort.Search(len(r.lines), func(i int) bool {
+		return offs < r.lines[i]
+	})
+	if i == 0 {
+		return 0, 0
+	}
+	return i, r.lines[i-1]
+}
+
+// content returns the bytes of line idx (which starts at start) up to upto,
+// and whether that content is available. Content may be unavailable if it
+// falls outside of the ring buffer and the underlying reader is not an
+// io.ReaderAt.
+func (r *Reader) content(idx int, start, upto int64) ([]byte, bool) {
+	n := upto - start
+	if n <= 0 {
+		return nil, true
+	}
+	if r.ra != nil {
+		buf := make([]byte, n)
+		m, err := r.ra.ReadAt(buf, start)
+		if err != nil && err != io.EOF {
+			return nil, false
+		}
+		return buf[:m], true
+	}
+	if idx == len(r.lines) {
+		if int64(len(r.cur)) >= n {
+			return r.cur[:n], true
+		}
+		return nil, false
+	}
+	if e := r.ring[idx%ringSize]; e.idx == idx && int64(len(e.b)) >= n {
+		return e.b[:n], true
+	}
+	return nil, false
+}
+
+// fullLine is like content, but returns the entirety of line idx.
+func (r *Reader) fullLine(idx int, start int64) ([]byte, bool) {
+	if idx < len(r.lines) {
+		return r.content(idx, start, r.lines[idx])
+	}
+	return r.content(idx, start, r.offs)
+}
+
+// column returns the 1-based column after decoding b according to r's Mode.
+func (r *Reader) column(b []byte) int {
+	width := 0
+	for len(b) > 0 {
+		ru, size := utf8.DecodeRune(b)
+		b = b[size:]
+		width = r.advance(width, ru)
+	}
+	return width + 1
+}
+
+// advance returns the display width after ru, given the width before it, for
+// the current Mode.
+func (r *Reader) advance(width int, ru rune) int {
+	switch {
+	case r.opts.Mode == ColumnsDisplay && ru == '\t':
+		return (width/r.opts.TabWidth + 1) * r.opts.TabWidth
+	case r.opts.Mode == ColumnsDisplay && unicode.Is(unicode.Mn, ru):
+		return width
+	default:
+		return width + 1
+	}
+}
+
+// columnOffset returns the byte offset of column within b, the inverse of
+// column.
+func (r *Reader) columnOffset(b []byte, column int) (int64, error) {
+	width := 0
+	var off int64
+	for len(b) > 0 && width+1 < column {
+		ru, size := utf8.DecodeRune(b)
+		width = r.advance(width, ru)
+		b = b[size:]
+		off += int64(size)
+	}
+	if width+1 != column {
+		return 0, fmt.Errorf("lines: column %d out of range", column)
+	}
+	return off, nil
+}
@@ -7,6 +7,145 @@ import (
 	"testing"
 )
 
+// noReaderAt hides any io.ReaderAt the wrapped reader implements, forcing
+// Reader onto its ring-buffer/cur fallback path instead of the ReadAt fast
+// path.
+type noReaderAt struct {
+	io.Reader
+}
+
+func TestPositionRunesBuffered(t *testing.T) {
+	// Same scenario as TestPositionRunes, but through a reader that is not
+	// an io.ReaderAt, so content() must serve offsets from cur and the ring
+	// buffer instead of re-reading the input.
+	in := "éé\nfoo\tbar\n日本語"
+	tcs := []struct {
+		mode     Mode
+		offs     int64
+		wantLine int
+		wantCol  int
+	}{
+		{ColumnsBytes, 4, 1, 5},
+		{ColumnsRunes, 4, 1, 3},
+		{ColumnsDisplay, 9, 2, 9},
+		{ColumnsRunes, 19, 3, 3},
+	}
+	for _, tc := range tcs {
+		r := NewReaderOptions(noReaderAt{strings.NewReader(in)}, Options{Mode: tc.mode})
+		io.Copy(io.Discard, r)
+		l, c := r.Position(tc.offs)
+		if l != tc.wantLine || c != tc.wantCol {
+			t.Errorf("Mode(%v).Position(%d) = (%d, %d), want (%d, %d)", tc.mode, tc.offs, l, c, tc.wantLine, tc.wantCol)
+		}
+	}
+}
+
+func TestRingEviction(t *testing.T) {
+	// More than ringSize completed lines, so the ring wraps around and
+	// evicts line 0's content (idx 0 and idx ringSize share a slot).
+	const n = ringSize + 8
+	in := strings.Repeat("é\n", n)
+	r := NewReaderOptions(noReaderAt{strings.NewReader(in)}, Options{Mode: ColumnsRunes})
+	io.Copy(io.Discard, r)
+
+	// Line 0 was evicted, so Position can't decode its rune content and
+	// falls back to a byte-based column.
+	if l, c := r.Position(2); l != 1 || c != 3 {
+		t.Errorf("Position(2) = (%d, %d), want (1, 3) for an evicted line", l, c)
+	}
+	// The last line is still in the ring, so its column reflects the
+	// actual, rune-decoded content.
+	lastOffs := int64(3*(n-1) + 2)
+	if l, c := r.Position(lastOffs); l != n || c != 2 {
+		t.Errorf("Position(%d) = (%d, %d), want (%d, 2) for a line still in the ring", lastOffs, l, c, n)
+	}
+}
+
+func TestPositionRunes(t *testing.T) {
+	// "é" is two bytes (U+00E9), "日" is three.
+	in := "éé\nfoo\tbar\n日本語"
+	tcs := []struct {
+		mode     Mode
+		offs     int64
+		wantLine int
+		wantCol  int
+	}{
+		{ColumnsBytes, 4, 1, 5},
+		{ColumnsRunes, 4, 1, 3},
+		{ColumnsDisplay, 9, 2, 9}, // 'b' in "foo\tbar", after the tab jumps to column 8
+		{ColumnsRunes, 19, 3, 3},  // right after "日本"
+	}
+	for _, tc := range tcs {
+		r := NewReaderOptions(strings.NewReader(in), Options{Mode: tc.mode})
+		io.Copy(io.Discard, r)
+		l, c := r.Position(tc.offs)
+		if l != tc.wantLine || c != tc.wantCol {
+			t.Errorf("Mode(%v).Position(%d) = (%d, %d), want (%d, %d)", tc.mode, tc.offs, l, c, tc.wantLine, tc.wantCol)
+		}
+	}
+}
+
+func TestOffset(t *testing.T) {
+	// ASCII content round-trips through Offset for ColumnsBytes.
+	r := NewReader(strings.NewReader("foo\nbar\nbaz"))
+	io.Copy(io.Discard, r)
+	tcs := []struct {
+		line, col int
+		want      int64
+	}{
+		{1, 1, 0}, {1, 4, 3}, {2, 1, 4}, {2, 2, 5}, {3, 1, 8}, {3, 4, 11},
+	}
+	for _, tc := range tcs {
+		got, err := r.Offset(tc.line, tc.col)
+		if err != nil || got != tc.want {
+			t.Errorf("Offset(%d, %d) = (%d, %v), want %d", tc.line, tc.col, got, err, tc.want)
+		}
+	}
+	if _, err := r.Offset(4, 1); err == nil {
+		t.Errorf("Offset(4, 1) succeeded, want error for a line that was never read")
+	}
+}
+
+func TestOffsetRunes(t *testing.T) {
+	// "é" is two bytes, "日" is three; Offset must count runes, not bytes.
+	r := NewReaderOptions(strings.NewReader("é日\nbar"), Options{Mode: ColumnsRunes})
+	io.Copy(io.Discard, r)
+	tcs := []struct {
+		line, col int
+		want      int64
+	}{
+		{1, 1, 0}, {1, 2, 2}, {1, 3, 5}, {2, 1, 6}, {2, 2, 7},
+	}
+	for _, tc := range tcs {
+		got, err := r.Offset(tc.line, tc.col)
+		if err != nil || got != tc.want {
+			t.Errorf("Offset(%d, %d) = (%d, %v), want %d", tc.line, tc.col, got, err, tc.want)
+		}
+	}
+}
+
+func TestCRLF(t *testing.T) {
+	tcs := []struct {
+		in       string
+		offs     int64
+		wantLine int
+		wantCol  int
+	}{
+		{"foo\r\nbar", 0, 1, 1},
+		{"foo\r\nbar", 5, 2, 1},
+		{"foo\rbar", 5, 2, 2},
+		{"foo\rbar\r\nbaz", 10, 3, 2},
+	}
+	for _, tc := range tcs {
+		r := NewReaderOptions(strings.NewReader(tc.in), Options{LineEndings: CRLF})
+		io.Copy(io.Discard, r)
+		l, c := r.Position(tc.offs)
+		if l != tc.wantLine || c != tc.wantCol {
+			t.Errorf("Position(%d, %q) = (%d, %d), want (%d, %d)", tc.offs, tc.in, l, c, tc.wantLine, tc.wantCol)
+		}
+	}
+}
+
 func TestLines(t *testing.T) {
 	tcs := []struct {
 		in       string
@@ -1,5 +1,14 @@
 package set
 
+import (
+	"encoding/json"
+	"iter"
+	"reflect"
+	"sort"
+
+	"gonih.org/cmp"
+)
+
 type Set[E comparable] map[E]struct{}
 
 func Make[E comparable](v ...E) Set[E] {
@@ -34,6 +43,14 @@ func Slurp[E comparable](ch <-chan E) Set[E] {
 	return s
 }
 
+func Collect[E comparable](seq iter.Seq[E]) Set[E] {
+	s := make(Set[E])
+	for v := range seq {
+		s.Add(v)
+	}
+	return s
+}
+
 func (s Set[E]) Add(v E) bool {
 	_, ok := s[v]
 	s[v] = struct{}{}
@@ -122,3 +139,171 @@ func (s Set[E]) SymmetricDifference(t Set[E]) Set[E] {
 	}
 	return out
 }
+
+func (s Set[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for v := range s {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func (s Set[E]) UnionSeq(t iter.Seq[E]) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for v := range s {
+			if !yield(v) {
+				return
+			}
+		}
+		seen := make(Set[E])
+		for v := range t {
+			if s.Contains(v) || !seen.Add(v) {
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func (s Set[E]) IntersectSeq(t iter.Seq[E]) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		seen := make(Set[E])
+		for v := range t {
+			if !s.Contains(v) || !seen.Add(v) {
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func (s Set[E]) DifferenceSeq(t iter.Seq[E]) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		other := Collect(t)
+		for v := range s {
+			if other.Contains(v) {
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func (s Set[E]) SymmetricDifferenceSeq(t iter.Seq[E]) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		other := Collect(t)
+		for v := range s {
+			if other.Contains(v) {
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+		for v := range other {
+			if s.Contains(v) {
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Sorted returns the elements of s sorted by cmp.
+func (s Set[E]) Sorted(cmp cmp.Cmp[E]) []E {
+	out := make([]E, 0, len(s))
+	for v := range s {
+		out = append(out, v)
+	}
+	sort.Slice(out, func(i, j int) bool { return cmp(out[i], out[j]) < 0 })
+	return out
+}
+
+// reflectLess compares two values via reflect, for the Ordered kinds
+// permitted by cmp.Ordered. It reports false for any other kind, so callers
+// must check reflectOrdered first.
+func reflectLess(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.String:
+		return a.String() < b.String()
+	default:
+		return false
+	}
+}
+
+func reflectOrdered(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.String:
+		return true
+	default:
+		return false
+	}
+}
+
+// commonOrderedKind reports whether every element of vals shares the same
+// Ordered kind (so that reflectLess can safely compare any two of them). E is
+// only constrained to comparable, so an element type such as any can mix
+// kinds within a single Set; those sets report false and sort by neither.
+func commonOrderedKind[E any](vals []E) bool {
+	if len(vals) == 0 {
+		return false
+	}
+	k := reflect.ValueOf(vals[0]).Kind()
+	if !reflectOrdered(k) {
+		return false
+	}
+	for _, v := range vals[1:] {
+		if reflect.ValueOf(v).Kind() != k {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalJSON serializes s as a JSON array. If every element shares the same
+// Ordered underlying kind (always the case when E is an Ordered type, and also
+// for e.g. a Set[any] holding only ints), the array is sorted for stable
+// output in tests and golden files; otherwise the order is the
+// non-deterministic map iteration order.
+func (s Set[E]) MarshalJSON() ([]byte, error) {
+	out := make([]E, 0, len(s))
+	for v := range s {
+		out = append(out, v)
+	}
+	if commonOrderedKind(out) {
+		sort.Slice(out, func(i, j int) bool {
+			return reflectLess(reflect.ValueOf(out[i]), reflect.ValueOf(out[j]))
+		})
+	}
+	return json.Marshal(out)
+}
+
+func (s *Set[E]) UnmarshalJSON(data []byte) error {
+	var vals []E
+	if err := json.Unmarshal(data, &vals); err != nil {
+		return err
+	}
+	*s = make(Set[E], len(vals))
+	for _, v := range vals {
+		s.Add(v)
+	}
+	return nil
+}
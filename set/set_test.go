@@ -0,0 +1,105 @@
+package set_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"gonih.org/cmp"
+	"gonih.org/set"
+)
+
+func TestUnionSeq(t *testing.T) {
+	s := set.Make(1, 2)
+	got := slices.Sorted(s.UnionSeq(slices.Values([]int{2, 3, 3, 4})))
+	want := []int{1, 2, 3, 4}
+	if !slices.Equal(got, want) {
+		t.Errorf("UnionSeq = %v, want %v", got, want)
+	}
+}
+
+func TestIntersectSeq(t *testing.T) {
+	s := set.Make(1, 2, 3)
+	got := slices.Sorted(s.IntersectSeq(slices.Values([]int{2, 2, 3, 3, 4})))
+	want := []int{2, 3}
+	if !slices.Equal(got, want) {
+		t.Errorf("IntersectSeq = %v, want %v", got, want)
+	}
+}
+
+func TestDifferenceSeq(t *testing.T) {
+	s := set.Make(1, 2, 3)
+	got := slices.Sorted(s.DifferenceSeq(slices.Values([]int{2, 2, 3})))
+	want := []int{1}
+	if !slices.Equal(got, want) {
+		t.Errorf("DifferenceSeq = %v, want %v", got, want)
+	}
+}
+
+func TestSymmetricDifferenceSeq(t *testing.T) {
+	s := set.Make(1, 2, 3)
+	got := slices.Sorted(s.SymmetricDifferenceSeq(slices.Values([]int{2, 2, 3, 4})))
+	want := []int{1, 4}
+	if !slices.Equal(got, want) {
+		t.Errorf("SymmetricDifferenceSeq = %v, want %v", got, want)
+	}
+}
+
+func TestDeleteFunc(t *testing.T) {
+	s := set.Make(1, 2, 3, 4, 5, 6)
+	s.DeleteFunc(func(v int) bool { return v%2 == 0 })
+	got := s.Sorted(cmp.Compare[int])
+	want := []int{1, 3, 5}
+	if !slices.Equal(got, want) {
+		t.Errorf("after DeleteFunc(even) = %v, want %v", got, want)
+	}
+}
+
+func TestSorted(t *testing.T) {
+	s := set.Make(3, 1, 2)
+	got := s.Sorted(cmp.Compare[int])
+	want := []int{1, 2, 3}
+	if !slices.Equal(got, want) {
+		t.Errorf("Sorted = %v, want %v", got, want)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	s := set.Make(3, 1, 2)
+	buf, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if got, want := string(buf), "[1,2,3]"; got != want {
+		t.Errorf("Marshal = %s, want %s (expected sorted for Ordered element type)", got, want)
+	}
+
+	var got set.Set[int]
+	if err := json.Unmarshal(buf, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !got.Contains(1) || !got.Contains(2) || !got.Contains(3) || len(got) != 3 {
+		t.Errorf("Unmarshal = %v, want %v", got, s)
+	}
+}
+
+func TestJSONMarshalMixedKind(t *testing.T) {
+	// A Set[any] can hold elements of different underlying kinds, since
+	// comparable no longer implies Ordered. Marshaling must not assume the
+	// whole set shares int's (or any other) reflect.Kind just because the
+	// first element ranged over happens to have it.
+	s := set.Make[any](1, "two", 3)
+	for i := 0; i < 20; i++ {
+		buf, err := json.Marshal(s)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		var got []any
+		if err := json.Unmarshal(buf, &got); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		if len(got) != 3 {
+			t.Errorf("Marshal round trip = %v, want 3 elements", got)
+		}
+	}
+}